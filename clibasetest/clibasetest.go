@@ -0,0 +1,179 @@
+// Package clibasetest provides helpers for writing table-driven tests against commands built
+// with clibase.New/NewUsingCmd, without leaking process state (os.Args, the environment, or
+// flag values) between test cases
+package clibasetest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// RunWithArgs executes cmd with args, temporarily setting the given environment variables, and
+// returns everything written to cmd's stdout/stderr. os.Args, the environment, and any flag
+// values mutated along the way (e.g. by clibase.SetFlagsFromEnvWithOverrides or BindConfigFile)
+// are snapshotted beforehand and restored before RunWithArgs returns, so cmd can be reused by
+// later test cases
+func RunWithArgs(cmd *cobra.Command, args []string, env map[string]string) (stdout, stderr string, err error) {
+	restoreArgs := snapshotArgs(args)
+	defer restoreArgs()
+
+	restoreEnv := snapshotEnv(env)
+	defer restoreEnv()
+
+	// cobra only merges persistent flags into a command's Flags() while parsing, and args may
+	// invoke a subcommand, so every flag set in cmd's tree is snapshotted
+	var restoreFlags []func()
+	var snapshotTree func(c *cobra.Command)
+	snapshotTree = func(c *cobra.Command) {
+		restoreFlags = append(restoreFlags, snapshotFlags(c.Flags()), snapshotFlags(c.PersistentFlags()))
+		for _, sub := range c.Commands() {
+			snapshotTree(sub)
+		}
+	}
+	snapshotTree(cmd)
+	defer func() {
+		for _, restore := range restoreFlags {
+			restore()
+		}
+	}()
+
+	var outBuf, errBuf bytes.Buffer
+	cmd.SetOut(&outBuf)
+	cmd.SetErr(&errBuf)
+	cmd.SetArgs(args)
+
+	err = cmd.Execute()
+	return outBuf.String(), errBuf.String(), err
+}
+
+// snapshotArgs replaces os.Args[1:] with args and returns a func that restores the original value
+func snapshotArgs(args []string) func() {
+	orig := os.Args
+	os.Args = append([]string{orig[0]}, args...)
+	return func() {
+		os.Args = orig
+	}
+}
+
+// snapshotEnv sets each key in env, returning a func that restores whatever was previously set
+// (or unsets the key if it wasn't set before)
+func snapshotEnv(env map[string]string) func() {
+	type original struct {
+		value string
+		set   bool
+	}
+	originals := make(map[string]original, len(env))
+
+	for key, value := range env {
+		orig, ok := os.LookupEnv(key)
+		originals[key] = original{orig, ok}
+		os.Setenv(key, value)
+	}
+
+	return func() {
+		for key, orig := range originals {
+			if orig.set {
+				os.Setenv(key, orig.value)
+			} else {
+				os.Unsetenv(key)
+			}
+		}
+	}
+}
+
+// flagSnapshot captures a single flag's value and Changed state. Slice-typed flags (StringSlice,
+// IntSlice, ...) are captured via pflag's SliceValue interface and restored with Replace rather
+// than Set, since Set on an already-changed slice value appends instead of overwriting
+type flagSnapshot struct {
+	value   string
+	slice   []string
+	isSlice bool
+	changed bool
+}
+
+// snapshotFlags records the current value and Changed state of every flag in flags, returning a
+// func that restores them
+func snapshotFlags(flags *pflag.FlagSet) func() {
+	originals := make(map[string]flagSnapshot)
+
+	flags.VisitAll(func(flag *pflag.Flag) {
+		snap := flagSnapshot{changed: flag.Changed}
+		if sv, ok := flag.Value.(pflag.SliceValue); ok {
+			snap.isSlice = true
+			snap.slice = append([]string(nil), sv.GetSlice()...)
+		} else {
+			snap.value = flag.Value.String()
+		}
+		originals[flag.Name] = snap
+	})
+
+	return func() {
+		flags.VisitAll(func(flag *pflag.Flag) {
+			snap, ok := originals[flag.Name]
+			if !ok {
+				return
+			}
+			if snap.isSlice {
+				if sv, ok := flag.Value.(pflag.SliceValue); ok {
+					_ = sv.Replace(snap.slice)
+				}
+			} else {
+				_ = flag.Value.Set(snap.value)
+			}
+			flag.Changed = snap.changed
+		})
+	}
+}
+
+// WriteTempConfig writes vals to a temporary directory as a config file in the given format
+// (toml, yaml, or json), returning the directory so it can be passed to --config-dir, plus a
+// cleanup func that removes it. Callers should always invoke cleanup, typically via defer,
+// whether or not WriteTempConfig returns an error
+func WriteTempConfig(vals map[string]string, format string) (dir string, cleanup func(), err error) {
+	tmpDir, err := os.MkdirTemp("", "clibasetest-")
+	if err != nil {
+		return "", func() {}, fmt.Errorf("creating temp config dir: %w", err)
+	}
+	cleanup = func() {
+		os.RemoveAll(tmpDir)
+	}
+
+	var data []byte
+	var fileName string
+
+	switch format {
+	case "toml":
+		var b strings.Builder
+		for key, value := range vals {
+			fmt.Fprintf(&b, "%s = %q\n", key, value)
+		}
+		data = []byte(b.String())
+		fileName = "config.toml"
+	case "yaml", "yml":
+		data, err = yaml.Marshal(vals)
+		fileName = "config.yaml"
+	case "json":
+		data, err = json.MarshalIndent(vals, "", "  ")
+		fileName = "config.json"
+	default:
+		return "", cleanup, fmt.Errorf("unsupported config format %q", format)
+	}
+	if err != nil {
+		return "", cleanup, fmt.Errorf("marshaling config: %w", err)
+	}
+
+	path := filepath.Join(tmpDir, fileName)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return "", cleanup, fmt.Errorf("writing config file %s: %w", path, err)
+	}
+
+	return tmpDir, cleanup, nil
+}
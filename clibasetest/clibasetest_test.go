@@ -0,0 +1,63 @@
+package clibasetest
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestWriteTempConfigCleanup(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		dir, cleanup, err := WriteTempConfig(map[string]string{"greeting": "hello"}, "yaml")
+		if err != nil {
+			t.Fatalf("WriteTempConfig: %v", err)
+		}
+		if _, err := os.Stat(dir); err != nil {
+			t.Fatalf("expected temp dir to exist: %v", err)
+		}
+
+		cleanup()
+
+		if _, err := os.Stat(dir); !os.IsNotExist(err) {
+			t.Fatalf("expected cleanup to remove %s, stat returned: %v", dir, err)
+		}
+	})
+
+	t.Run("unsupported format still cleans up", func(t *testing.T) {
+		dir, cleanup, err := WriteTempConfig(map[string]string{"greeting": "hello"}, "bogus-format")
+		if err == nil {
+			t.Fatal("expected an error for an unsupported format")
+		}
+		if dir != "" {
+			t.Fatalf("expected no directory to be returned, got %q", dir)
+		}
+
+		before := tempDirCount(t)
+		cleanup()
+		after := tempDirCount(t)
+
+		if after >= before {
+			t.Fatalf("expected cleanup to remove the temp dir it created (before=%d, after=%d)", before, after)
+		}
+	})
+}
+
+// tempDirCount counts how many clibasetest-* directories currently exist under os.TempDir, so
+// a test can confirm cleanup actually removed the one it created even though WriteTempConfig's
+// own return value was blanked out on the error path under test
+func tempDirCount(t *testing.T) int {
+	t.Helper()
+
+	entries, err := os.ReadDir(os.TempDir())
+	if err != nil {
+		t.Fatalf("reading temp dir: %v", err)
+	}
+
+	count := 0
+	for _, entry := range entries {
+		if entry.IsDir() && strings.HasPrefix(entry.Name(), "clibasetest-") {
+			count++
+		}
+	}
+	return count
+}
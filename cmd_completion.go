@@ -0,0 +1,108 @@
+package clibase
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// RegisterFlagCompletion registers fn as the dynamic completion function for flagName on cmd,
+// validating that the flag exists first. It is a thin wrapper around
+// cmd.RegisterFlagCompletionFunc that logs a warning (rather than returning an error that could
+// be ignored) when flagName doesn't exist, since that's almost always a typo
+func RegisterFlagCompletion(cmd *cobra.Command, flagName string, fn func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)) {
+	if cmd.Flags().Lookup(flagName) == nil && cmd.InheritedFlags().Lookup(flagName) == nil {
+		log.WithFields(log.Fields{
+			"command.name": cmd.Name(),
+			"flag.name":    flagName,
+		}).Warn("cannot register completion for unknown flag")
+		return
+	}
+
+	if err := cmd.RegisterFlagCompletionFunc(flagName, fn); err != nil {
+		log.WithFields(log.Fields{
+			"command.name": cmd.Name(),
+			"flag.name":    flagName,
+			"error":        err,
+		}).Warn("failed to register flag completion")
+	}
+}
+
+// addCompletionCmd registers a "completion" subcommand that emits a shell completion script for
+// bash, zsh, fish, or powershell
+func addCompletionCmd(rootCmd *cobra.Command) {
+	completionCmd := &cobra.Command{
+		Use:       "completion [bash|zsh|fish|powershell]",
+		Short:     "generate a shell completion script",
+		ValidArgs: []string{"bash", "zsh", "fish", "powershell"},
+		Args:      cobra.ExactValidArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
+			switch args[0] {
+			case "bash":
+				return rootCmd.GenBashCompletionV2(out, true)
+			case "zsh":
+				return rootCmd.GenZshCompletion(out)
+			case "fish":
+				return genFishCompletion(out, rootCmd)
+			case "powershell":
+				return rootCmd.GenPowerShellCompletionWithDesc(out)
+			}
+			return fmt.Errorf("unsupported shell %q", args[0])
+		},
+	}
+
+	rootCmd.AddCommand(completionCmd)
+}
+
+// unescapedLastArgPattern matches cobra's fish template assigning the partial word being
+// completed (lastArg) to an unescaped expression. cobra composes lastArg directly into the
+// string it later hands to "eval" to re-tokenize into a command + arguments; an unescaped
+// lastArg containing fish metacharacters such as "$(...)" would then be re-interpreted by eval as
+// live command substitution rather than a literal argument. The pattern also matches an
+// assignment that already goes through "string escape"; escapeFishLastArg recognizes those via
+// alreadyEscapedPattern and leaves them as they are, so this is a no-op against cobra versions
+// that already escape lastArg themselves.
+var (
+	unescapedLastArgPattern = regexp.MustCompile(`(?m)^(\s*set -l lastArg )\(((?:commandline|string)[^\n]*)\)\s*$`)
+	alreadyEscapedPattern   = regexp.MustCompile(`^string escape\b`)
+)
+
+// genFishCompletion generates rootCmd's fish completion script and, if needed, patches around a
+// known fish quirk: cobra's template runs the completion helper via "eval $requestComp", which is
+// required to re-tokenize the composed "set env var, then run command with args" string rather
+// than bash-style word-splitting it (fish doesn't word-split unquoted variables). Because that
+// string embeds lastArg unescaped, this wraps the lastArg assignment in fish's own `string
+// escape` so eval sees a literal value instead of one it can re-interpret; the "eval" call itself
+// is left in place since removing it stops the helper from running at all
+func genFishCompletion(w io.Writer, rootCmd *cobra.Command) error {
+	var buf bytes.Buffer
+	if err := rootCmd.GenFishCompletion(&buf, true); err != nil {
+		return err
+	}
+
+	script := escapeFishLastArg(buf.String())
+
+	_, err := io.WriteString(w, script)
+	return err
+}
+
+// escapeFishLastArg wraps an unescaped "set -l lastArg (...)" assignment in `string escape --`,
+// leaving the script untouched if lastArg is already escaped
+func escapeFishLastArg(script string) string {
+	return unescapedLastArgPattern.ReplaceAllStringFunc(script, func(line string) string {
+		match := unescapedLastArgPattern.FindStringSubmatch(line)
+		if match == nil {
+			return line
+		}
+		prefix, expr := match[1], match[2]
+		if alreadyEscapedPattern.MatchString(expr) {
+			return line
+		}
+		return fmt.Sprintf("%s(string escape -- (%s))", prefix, expr)
+	})
+}
@@ -0,0 +1,188 @@
+package clibase
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	configFlagName    = "config"
+	configDirFlagName = "config-dir"
+)
+
+var configFileExtensions = []string{"toml", "yaml", "yml", "json"}
+
+// ConfigOptions controls how BindConfigFile locates and applies a configuration file.
+type ConfigOptions struct {
+	// Name is the base file name (without extension) BindConfigFile looks for inside
+	// --config-dir when --config is not set explicitly. Defaults to cmd.Name() when empty.
+	Name string
+}
+
+// addConfigFlags adds the --config and --config-dir flags to the given flag set
+func addConfigFlags(flags *pflag.FlagSet) {
+	confFlags := &pflag.FlagSet{}
+
+	confFlags.String(configFlagName, "", "path to a config file (toml, yaml, or json)")
+	confFlags.String(configDirFlagName, "", "directory to search for a config file when --config is not set")
+
+	SetFlagsFromEnv(cliBaseFlagPrefix, confFlags)
+
+	flags.AddFlagSet(confFlags)
+}
+
+// BindConfigFile loads a TOML/YAML/JSON configuration file (auto-detected by extension) and
+// applies its values onto cmd's flags. NewUsingCmd calls it automatically from
+// rootPersistentPreRunE, so it runs after cobra has parsed the command line (--config/--config-dir
+// aren't resolvable any earlier, since persistent flags aren't merged into cmd.Flags() until
+// cobra parses the invoked command) but before the command's own RunE. Any flag the user passed
+// explicitly on the command line (flag.Changed) is left untouched, so the resulting precedence is:
+// CLI flag > environment variable > config file > flag default, as long as
+// SetFlagsFromEnvWithOverrides is called from a PersistentPreRunE set on the command before it is
+// passed to NewUsingCmd (NewUsingCmd chains that one to run after rootPersistentPreRunE; one set
+// afterward replaces rootPersistentPreRunE instead).
+//
+// Nested keys are flattened and hyphenated to match flag naming, so a file containing
+// server.listen-addr maps to the --server-listen-addr flag. Keys with no matching flag produce a
+// warning via logrus rather than an error, since config files are often shared across tools.
+func BindConfigFile(cmd *cobra.Command, opts ConfigOptions) error {
+	flags := cmd.Flags()
+
+	configPath, err := flags.GetString(configFlagName)
+	if err != nil {
+		LogFlagError(configFlagName, err)
+		return err
+	}
+	configDir, err := flags.GetString(configDirFlagName)
+	if err != nil {
+		LogFlagError(configDirFlagName, err)
+		return err
+	}
+
+	if configPath == "" {
+		if configDir == "" {
+			return nil
+		}
+		name := opts.Name
+		if name == "" {
+			name = cmd.Name()
+		}
+		configPath = findConfigFile(configDir, name)
+		if configPath == "" {
+			return nil
+		}
+	}
+
+	values, err := loadConfigFile(configPath)
+	if err != nil {
+		return err
+	}
+
+	applyConfigValues(flags, configPath, flattenConfigKeys("", values))
+	return nil
+}
+
+// findConfigFile returns the first file in dir named name.<ext> for each supported extension,
+// or an empty string if none exist
+func findConfigFile(dir, name string) string {
+	for _, ext := range configFileExtensions {
+		candidate := filepath.Join(dir, fmt.Sprintf("%s.%s", name, ext))
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// loadConfigFile reads path and unmarshals it into a generic map, based on its extension
+func loadConfigFile(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config file %s: %w", path, err)
+	}
+
+	values := map[string]interface{}{}
+	switch ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(path), ".")); ext {
+	case "toml":
+		err = toml.Unmarshal(data, &values)
+	case "yaml", "yml":
+		err = yaml.Unmarshal(data, &values)
+	case "json":
+		err = json.Unmarshal(data, &values)
+	default:
+		return nil, fmt.Errorf("unsupported config file extension %q", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// flattenConfigKeys recursively joins nested map keys with a hyphen, so that
+// {"server": {"listen-addr": ":8080"}} becomes {"server-listen-addr": ":8080"}
+func flattenConfigKeys(prefix string, m map[string]interface{}) map[string]interface{} {
+	flat := map[string]interface{}{}
+	for k, v := range m {
+		key := k
+		if prefix != "" {
+			key = prefix + "-" + k
+		}
+
+		switch nested := v.(type) {
+		case map[string]interface{}:
+			for nk, nv := range flattenConfigKeys(key, nested) {
+				flat[nk] = nv
+			}
+		case map[interface{}]interface{}:
+			converted := make(map[string]interface{}, len(nested))
+			for nk, nv := range nested {
+				converted[fmt.Sprintf("%v", nk)] = nv
+			}
+			for nk, nv := range flattenConfigKeys(key, converted) {
+				flat[nk] = nv
+			}
+		default:
+			flat[key] = v
+		}
+	}
+	return flat
+}
+
+// applyConfigValues sets each flattened config value onto its matching flag, warning via logrus
+// for any key that has no corresponding flag. A flag the user passed on the command line
+// (flag.Changed) is left alone. Values applied by SetFlagsFromEnvWithOverrides don't mark a flag
+// Changed, so an environment value set before this runs is overwritten by the config file value;
+// the environment only wins when SetFlagsFromEnvWithOverrides runs afterward
+func applyConfigValues(flags *pflag.FlagSet, configPath string, values map[string]interface{}) {
+	for key, value := range values {
+		logLn := log.WithFields(log.Fields{
+			"config.file":  configPath,
+			"config.key":   key,
+			"config.value": value,
+		})
+
+		flag := flags.Lookup(key)
+		if flag == nil {
+			logLn.Warn("config file key has no matching flag")
+			continue
+		}
+
+		if flag.Changed {
+			logLn.Debug("flag already set explicitly, ignoring config file value")
+			continue
+		}
+
+		if err := flag.Value.Set(fmt.Sprintf("%v", value)); err != nil {
+			logLn.WithField("error", err).Warn("failed to set flag from config file")
+		}
+	}
+}
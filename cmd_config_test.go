@@ -0,0 +1,70 @@
+package clibase
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestConfigPrecedence(t *testing.T) {
+	tests := []struct {
+		name    string
+		cliArgs []string
+		envVal  string
+		want    string
+	}{
+		{
+			name:    "explicit CLI flag beats env var and config file",
+			cliArgs: []string{"--greeting", "from-cli"},
+			envVal:  "from-env",
+			want:    "from-cli",
+		},
+		{
+			name:   "env var beats config file",
+			want:   "from-env",
+			envVal: "from-env",
+		},
+		{
+			name: "config file beats flag default",
+			want: "from-config",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{Use: "testcmd"}
+			addConfigFlags(cmd.Flags())
+			cmd.Flags().String("greeting", "default-value", "greeting to use")
+
+			dir := t.TempDir()
+			configPath := filepath.Join(dir, "config.yaml")
+			if err := os.WriteFile(configPath, []byte("greeting: from-config\n"), 0o600); err != nil {
+				t.Fatalf("writing test config: %v", err)
+			}
+
+			args := append([]string{"--config", configPath}, tt.cliArgs...)
+			if err := cmd.Flags().Parse(args); err != nil {
+				t.Fatalf("parsing flags: %v", err)
+			}
+
+			if tt.envVal != "" {
+				t.Setenv("TEST_GREETING", tt.envVal)
+			}
+
+			if err := BindConfigFile(cmd, ConfigOptions{}); err != nil {
+				t.Fatalf("BindConfigFile: %v", err)
+			}
+			SetFlagsFromEnvWithOverrides("", cmd.Flags(), map[string]string{"greeting": "TEST_GREETING"})
+
+			got, err := cmd.Flags().GetString("greeting")
+			if err != nil {
+				t.Fatalf("GetString: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("greeting = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
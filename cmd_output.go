@@ -0,0 +1,17 @@
+package clibase
+
+import (
+	"github.com/SkyMack/clibase/output"
+	"github.com/spf13/pflag"
+)
+
+// addOutputFlags adds the --output/-o flag to the given flag set
+func addOutputFlags(flags *pflag.FlagSet) {
+	outFlags := &pflag.FlagSet{}
+
+	outFlags.StringP(output.FlagName, "o", output.DefaultFormat, "output format (text, json, yaml, or a renderer registered via output.RegisterRenderer)")
+
+	SetFlagsFromEnv(cliBaseFlagPrefix, outFlags)
+
+	flags.AddFlagSet(outFlags)
+}
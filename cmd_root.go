@@ -25,6 +25,8 @@ func AddTopLevelFlags(flags *pflag.FlagSet) {
 	topLevelFlags := &pflag.FlagSet{}
 
 	addLogFlags(topLevelFlags)
+	addConfigFlags(topLevelFlags)
+	addOutputFlags(topLevelFlags)
 	flags.AddFlagSet(topLevelFlags)
 }
 
@@ -55,6 +57,13 @@ func NewUsingCmd(rootCmd *cobra.Command) *cobra.Command {
 	rootCmd.PersistentPreRunE = persistentPreRunE
 	AddTopLevelFlags(rootCmd.PersistentFlags())
 	addVersionCmd(rootCmd)
+	// Adding subcommands/flags here never touches rootCmd.ValidArgsFunction or flag completion
+	// funcs registered via RegisterFlagCompletion, so callers can set those up before or after
+	// NewUsingCmd without losing them
+	addCompletionCmd(rootCmd)
+	// Runs last so its DisableSuggestions/SuggestionsMinimumDistance wiring reaches the version
+	// and completion subcommands added above
+	enableSuggestions(rootCmd)
 	return rootCmd
 }
 
@@ -67,6 +76,10 @@ func LogFlagError(flagName string, err error) {
 }
 
 func rootPersistentPreRunE(cmd *cobra.Command, args []string) error {
+	if err := BindConfigFile(cmd, ConfigOptions{Name: cmd.Root().Name()}); err != nil {
+		return err
+	}
+
 	flags := cmd.Flags()
 	logFormat, err := flags.GetString(logFlagFormatName)
 	if err != nil {
@@ -114,6 +127,8 @@ func checkCobraFlags(flags *pflag.FlagSet) {
 // Override is for when you want all the flags to have a prefix, but need some specific flags to not use that prefix
 // (or have different env vars entirely). For example, having the application recognize a standard env var like "REDIS_URL"
 // while prefacing most app specific flags with APPNAME_
+// A flag the user already set explicitly on the command line (flag.Changed) is left alone, so an
+// explicit CLI flag always wins over the environment variable.
 func SetFlagsFromEnvWithOverrides(prefix string, flagSet *pflag.FlagSet, overrides map[string]string) {
 	flagSet.VisitAll(func(flag *pflag.Flag) {
 		envName, ok := overrides[flag.Name]
@@ -135,6 +150,11 @@ func SetFlagsFromEnvWithOverrides(prefix string, flagSet *pflag.FlagSet, overrid
 			return
 		}
 
+		if flag.Changed {
+			logLn.Debug("flag already set explicitly, ignoring environment value")
+			return
+		}
+
 		logLn = logLn.WithField("env.value", value)
 
 		logLn.Debug("Updating with the environment value")
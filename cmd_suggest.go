@@ -0,0 +1,322 @@
+package clibase
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	defaultSuggestionThreshold = 0.7
+	defaultMaxSuggestions      = 3
+)
+
+// SuggestionOptions controls the did-you-mean subsystem wired into NewUsingCmd
+type SuggestionOptions struct {
+	// Enabled turns suggestions on or off
+	Enabled bool
+	// Threshold is the minimum Jaro-Winkler similarity (0-1) a candidate must have to be suggested
+	Threshold float64
+	// MaxSuggestions caps how many candidates are shown
+	MaxSuggestions int
+}
+
+// DefaultSuggestionOptions returns the SuggestionOptions applied automatically by NewUsingCmd
+func DefaultSuggestionOptions() SuggestionOptions {
+	return SuggestionOptions{
+		Enabled:        true,
+		Threshold:      defaultSuggestionThreshold,
+		MaxSuggestions: defaultMaxSuggestions,
+	}
+}
+
+var (
+	suggestionOptionsMu sync.Mutex
+	suggestionOptions   = map[*cobra.Command]SuggestionOptions{}
+)
+
+// SetSuggestionOptions overrides the SuggestionOptions used for cmd and its subcommands. Pass it
+// a command returned by New or NewUsingCmd to customize the threshold, suggestion count, or to
+// disable the subsystem entirely.
+//
+// cobra resolves unknown-subcommand errors (and decides whether/how to suggest a fix for them)
+// while walking the command tree, before any of our hooks run, so there's no event to react to
+// at that point the way flagSuggestionErrorFunc reacts to flag errors. Instead, SetSuggestionOptions
+// immediately pushes Enabled/Threshold onto cmd.DisableSuggestions/SuggestionsMinimumDistance for
+// cmd and every subcommand registered on it so far. Subcommands added to cmd afterward won't pick
+// up a customized threshold automatically; call SetSuggestionOptions again once they're all
+// registered if that matters.
+func SetSuggestionOptions(cmd *cobra.Command, opts SuggestionOptions) {
+	suggestionOptionsMu.Lock()
+	suggestionOptions[cmd] = opts
+	suggestionOptionsMu.Unlock()
+
+	applyCobraSuggestionFields(cmd, opts)
+}
+
+// applyCobraSuggestionFields pushes opts onto the handful of cobra.Command fields that drive its
+// own built-in unknown-command suggestions, recursing into every registered subcommand
+func applyCobraSuggestionFields(cmd *cobra.Command, opts SuggestionOptions) {
+	cmd.DisableSuggestions = !opts.Enabled
+	cmd.SuggestionsMinimumDistance = suggestionMinimumDistance(opts.Threshold)
+	for _, sub := range cmd.Commands() {
+		applyCobraSuggestionFields(sub, opts)
+	}
+}
+
+// suggestionMinimumDistance maps our 0-1 Jaro-Winkler-style Threshold onto the small integer
+// Levenshtein distance ceiling cobra's own suggestion engine uses: a higher threshold (stricter)
+// means a smaller maximum distance
+func suggestionMinimumDistance(threshold float64) int {
+	distance := int((1 - threshold) * 10)
+	if distance < 1 {
+		distance = 1
+	}
+	return distance
+}
+
+// getSuggestionOptions walks up from cmd to its root looking for options registered via
+// SetSuggestionOptions, falling back to DefaultSuggestionOptions when none were set
+func getSuggestionOptions(cmd *cobra.Command) SuggestionOptions {
+	suggestionOptionsMu.Lock()
+	defer suggestionOptionsMu.Unlock()
+
+	for c := cmd; c != nil; c = c.Parent() {
+		if opts, ok := suggestionOptions[c]; ok {
+			return opts
+		}
+	}
+	return DefaultSuggestionOptions()
+}
+
+// enableSuggestions wires the did-you-mean subsystem into rootCmd: unknown flags are caught via
+// a FlagErrorFunc (pflag has no built-in suggestion support), and unknown subcommands are handled
+// by cobra's own suggestion engine, tuned to DefaultSuggestionOptions via SetSuggestionOptions.
+// Call it after all of rootCmd's subcommands have been registered, since SetSuggestionOptions
+// only reaches commands that exist at the time it runs
+func enableSuggestions(rootCmd *cobra.Command) {
+	rootCmd.SetFlagErrorFunc(flagSuggestionErrorFunc)
+	SetSuggestionOptions(rootCmd, DefaultSuggestionOptions())
+}
+
+var (
+	// pflag.FlagSet.Parse reports an unrecognized long flag as "unknown flag: --foo"
+	unknownFlagPattern = regexp.MustCompile(`^unknown flag: -+(\S+)`)
+	// and an unrecognized shorthand as "unknown shorthand flag: 'f' in -foo", where the quoted
+	// character is the single shorthand letter that isn't registered (the rest of "-foo" may be
+	// other, valid, shorthands bundled in the same argument)
+	unknownShorthandFlagPattern = regexp.MustCompile(`^unknown shorthand flag: '(.)' in`)
+)
+
+// flagSuggestionErrorFunc appends a "Did you mean" hint for unknown-flag errors, based on the
+// flag names (or, for shorthand typos, the single-letter shorthands) registered on the offending
+// command. It leaves the error (and cobra's own SilenceErrors/usage printing) untouched otherwise
+func flagSuggestionErrorFunc(cmd *cobra.Command, err error) error {
+	opts := getSuggestionOptions(cmd)
+	if !opts.Enabled {
+		return err
+	}
+
+	var (
+		input      string
+		candidates []string
+		format     func([]string) string
+	)
+	switch {
+	case unknownFlagPattern.MatchString(err.Error()):
+		match := unknownFlagPattern.FindStringSubmatch(err.Error())
+		input, candidates, format = match[1], allFlagNames(cmd), formatFlagSuggestions
+	case unknownShorthandFlagPattern.MatchString(err.Error()):
+		match := unknownShorthandFlagPattern.FindStringSubmatch(err.Error())
+		input, candidates, format = match[1], allFlagShorthands(cmd), formatShorthandSuggestions
+	default:
+		return err
+	}
+
+	suggestions := Suggest(input, candidates, opts.Threshold)
+	if len(suggestions) == 0 {
+		return err
+	}
+	if len(suggestions) > opts.MaxSuggestions {
+		suggestions = suggestions[:opts.MaxSuggestions]
+	}
+
+	return fmt.Errorf("%w\n\nDid you mean %s?", err, format(suggestions))
+}
+
+// allFlagNames returns the names of every flag registered on cmd, including inherited ones
+func allFlagNames(cmd *cobra.Command) []string {
+	var names []string
+	seen := map[string]bool{}
+	visit := func(flag *pflag.Flag) {
+		if seen[flag.Name] {
+			return
+		}
+		seen[flag.Name] = true
+		names = append(names, flag.Name)
+	}
+	cmd.Flags().VisitAll(visit)
+	cmd.InheritedFlags().VisitAll(visit)
+	return names
+}
+
+// allFlagShorthands returns the single-letter shorthand of every flag registered on cmd that has
+// one, including inherited flags
+func allFlagShorthands(cmd *cobra.Command) []string {
+	var shorthands []string
+	seen := map[string]bool{}
+	visit := func(flag *pflag.Flag) {
+		if flag.Shorthand == "" || seen[flag.Shorthand] {
+			return
+		}
+		seen[flag.Shorthand] = true
+		shorthands = append(shorthands, flag.Shorthand)
+	}
+	cmd.Flags().VisitAll(visit)
+	cmd.InheritedFlags().VisitAll(visit)
+	return shorthands
+}
+
+func formatFlagSuggestions(names []string) string {
+	prefixed := make([]string, len(names))
+	for i, name := range names {
+		prefixed[i] = "--" + name
+	}
+	return strings.Join(prefixed, ", ")
+}
+
+func formatShorthandSuggestions(shorthands []string) string {
+	prefixed := make([]string, len(shorthands))
+	for i, s := range shorthands {
+		prefixed[i] = "-" + s
+	}
+	return strings.Join(prefixed, ", ")
+}
+
+// Suggest returns the entries of candidates whose Jaro-Winkler similarity to input is at or
+// above threshold (0-1), ordered from most to least similar. The comparison is case-insensitive
+// (matched candidates keep their original casing) so a case typo - the most common way to mistype
+// a single-letter flag shorthand - still scores as a match. It is exported so downstream commands
+// can reuse it for their own argument validation
+func Suggest(input string, candidates []string, threshold float64) []string {
+	type scored struct {
+		name  string
+		score float64
+	}
+
+	lowerInput := strings.ToLower(input)
+	var matches []scored
+	for _, candidate := range candidates {
+		score := jaroWinkler(lowerInput, strings.ToLower(candidate))
+		if score >= threshold {
+			matches = append(matches, scored{candidate, score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+
+	if len(matches) == 0 {
+		return nil
+	}
+	suggestions := make([]string, len(matches))
+	for i, m := range matches {
+		suggestions[i] = m.name
+	}
+	return suggestions
+}
+
+// jaroWinkler computes the Jaro-Winkler similarity of s1 and s2, a value between 0 (no
+// similarity) and 1 (identical)
+func jaroWinkler(s1, s2 string) float64 {
+	jaro := jaroSimilarity(s1, s2)
+	if jaro == 0 {
+		return 0
+	}
+
+	const (
+		prefixScale   = 0.1
+		maxPrefixSize = 4
+	)
+
+	prefix := 0
+	for i := 0; i < len(s1) && i < len(s2) && i < maxPrefixSize; i++ {
+		if s1[i] != s2[i] {
+			break
+		}
+		prefix++
+	}
+
+	return jaro + float64(prefix)*prefixScale*(1-jaro)
+}
+
+// jaroSimilarity computes the Jaro similarity of s1 and s2
+func jaroSimilarity(s1, s2 string) float64 {
+	if s1 == s2 {
+		return 1
+	}
+	len1, len2 := len(s1), len(s2)
+	if len1 == 0 || len2 == 0 {
+		return 0
+	}
+
+	matchDistance := len1/2 - 1
+	if len2/2-1 > matchDistance {
+		matchDistance = len2/2 - 1
+	}
+	if matchDistance < 0 {
+		matchDistance = 0
+	}
+
+	s1Matches := make([]bool, len1)
+	s2Matches := make([]bool, len2)
+
+	matches := 0
+	for i := 0; i < len1; i++ {
+		start := i - matchDistance
+		if start < 0 {
+			start = 0
+		}
+		end := i + matchDistance + 1
+		if end > len2 {
+			end = len2
+		}
+		for j := start; j < end; j++ {
+			if s2Matches[j] || s1[i] != s2[j] {
+				continue
+			}
+			s1Matches[i] = true
+			s2Matches[j] = true
+			matches++
+			break
+		}
+	}
+
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions := 0
+	k := 0
+	for i := 0; i < len1; i++ {
+		if !s1Matches[i] {
+			continue
+		}
+		for !s2Matches[k] {
+			k++
+		}
+		if s1[i] != s2[k] {
+			transpositions++
+		}
+		k++
+	}
+
+	m := float64(matches)
+	return (m/float64(len1) + m/float64(len2) + (m-float64(transpositions)/2)/m) / 3
+}
@@ -0,0 +1,39 @@
+package clibase
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSuggestShorthand(t *testing.T) {
+	shorthands := []string{"f", "a", "v"}
+
+	tests := []struct {
+		name      string
+		input     string
+		threshold float64
+		want      []string
+	}{
+		{
+			name:      "case typo matches the registered shorthand",
+			input:     "F",
+			threshold: defaultSuggestionThreshold,
+			want:      []string{"f"},
+		},
+		{
+			name:      "unrelated letter suggests nothing",
+			input:     "z",
+			threshold: defaultSuggestionThreshold,
+			want:      nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Suggest(tt.input, shorthands, tt.threshold)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Suggest(%q, %v, %v) = %v, want %v", tt.input, shorthands, tt.threshold, got, tt.want)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"runtime/debug"
 	"strings"
 
+	"github.com/SkyMack/clibase/output"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -15,7 +16,53 @@ const (
 	flagPackageScopeName = "package-prefix"
 )
 
-func version(name string, flags *pflag.FlagSet) error {
+// versionDep describes a single build dependency in version output
+type versionDep struct {
+	Path    string `json:"path" yaml:"path"`
+	Version string `json:"version" yaml:"version"`
+	Replace string `json:"replace,omitempty" yaml:"replace,omitempty"`
+}
+
+// versionInfo is the structured object rendered by the version command
+type versionInfo struct {
+	Name      string       `json:"name" yaml:"name"`
+	Path      string       `json:"path" yaml:"path"`
+	Version   string       `json:"version" yaml:"version"`
+	GoVersion string       `json:"go_version" yaml:"go_version"`
+	GOARCH    string       `json:"goarch" yaml:"goarch"`
+	GOOS      string       `json:"goos" yaml:"goos"`
+	Deps      []versionDep `json:"deps" yaml:"deps"`
+}
+
+// String renders versionInfo the same way the original printf-based version command did, so the
+// default "text" output format stays unchanged for existing users
+func (v versionInfo) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s (%s %s)\n", v.Name, v.Path, v.Version)
+	fmt.Fprintf(&b, "\n")
+	fmt.Fprintf(&b, "  Compiled with: %s\n", runtime.Compiler)
+	fmt.Fprintf(&b, "         GOARCH: %s\n", v.GOARCH)
+	fmt.Fprintf(&b, "           GOOS: %s\n", v.GOOS)
+	fmt.Fprintf(&b, "     Go Version: %s\n", v.GoVersion)
+	fmt.Fprintf(&b, "\n")
+
+	for _, dep := range v.Deps {
+		depLine := fmt.Sprintf("%s %s", dep.Path, dep.Version)
+		if dep.Replace != "" {
+			var struckthrough string
+			for _, r := range depLine {
+				struckthrough += "̶" + string(r)
+			}
+			depLine = fmt.Sprintf("%s̶  => %s", struckthrough, dep.Replace)
+		}
+		fmt.Fprintf(&b, "  %s\n", depLine)
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func version(cmd *cobra.Command, name string, flags *pflag.FlagSet) error {
 	packPrefix, err := flags.GetString(flagPackageScopeName)
 	if err != nil {
 		LogFlagError(flagPackageScopeName, err)
@@ -27,30 +74,28 @@ func version(name string, flags *pflag.FlagSet) error {
 		log.Debug("binary not built with module support")
 		return nil
 	}
-	fmt.Printf("%s (%s %s)\n", name, buildInfo.Main.Path, buildInfo.Main.Version)
 
-	fmt.Printf("\n")
-	fmt.Printf("  Compiled with: %s\n", runtime.Compiler)
-	fmt.Printf("         GOARCH: %s\n", runtime.GOARCH)
-	fmt.Printf("           GOOS: %s\n", runtime.GOOS)
-	fmt.Printf("     Go Version: %s\n", runtime.Version())
-	fmt.Printf("\n")
+	info := versionInfo{
+		Name:      name,
+		Path:      buildInfo.Main.Path,
+		Version:   buildInfo.Main.Version,
+		GoVersion: runtime.Version(),
+		GOARCH:    runtime.GOARCH,
+		GOOS:      runtime.GOOS,
+	}
 
 	for _, pkg := range buildInfo.Deps {
 		if !strings.HasPrefix(pkg.Path, packPrefix) {
 			continue
 		}
-		output := fmt.Sprintf("%s %s", pkg.Path, pkg.Version)
+		dep := versionDep{Path: pkg.Path, Version: pkg.Version}
 		if pkg.Replace != nil {
-			var struckthrough string
-			for _, r := range output {
-				struckthrough += "\u0336" + string(r)
-			}
-			output = fmt.Sprintf("%s\u0336  => %s", struckthrough, pkg.Replace.Path)
+			dep.Replace = pkg.Replace.Path
 		}
-		fmt.Printf("  %s\n", output)
+		info.Deps = append(info.Deps, dep)
 	}
-	return nil
+
+	return output.Render(cmd, info)
 }
 
 func addVersionFlags(flags *pflag.FlagSet) {
@@ -68,7 +113,7 @@ func addVersionCmd(rootCmd *cobra.Command) {
 		Use:   "version",
 		Short: "output the binary version and dependency details",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return version(rootCmd.Name(), cmd.Flags())
+			return version(cmd, rootCmd.Name(), cmd.Flags())
 		},
 	}
 
@@ -0,0 +1,93 @@
+// Package output provides pluggable rendering of command results, so RunE implementations can
+// emit a single value and let the caller decide whether it comes out as text, JSON, YAML, or a
+// custom format registered via RegisterRenderer
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// FlagName is the name of the persistent flag clibase adds for selecting an output format
+const FlagName = "output"
+
+// DefaultFormat is the renderer name used when --output is not set
+const DefaultFormat = "text"
+
+// Renderer writes v to w in a particular format
+type Renderer interface {
+	Render(w io.Writer, v interface{}) error
+}
+
+// RendererFunc adapts a function to the Renderer interface
+type RendererFunc func(w io.Writer, v interface{}) error
+
+// Render calls fn(w, v)
+func (fn RendererFunc) Render(w io.Writer, v interface{}) error {
+	return fn(w, v)
+}
+
+var (
+	renderersMu sync.Mutex
+	renderers   = map[string]Renderer{}
+)
+
+func init() {
+	RegisterRenderer(DefaultFormat, RendererFunc(renderText))
+	RegisterRenderer("json", RendererFunc(renderJSON))
+	RegisterRenderer("yaml", RendererFunc(renderYAML))
+}
+
+// RegisterRenderer makes a Renderer available under name for use with the --output flag. It is
+// typically called from an init() function by packages that want to add their own output format
+func RegisterRenderer(name string, r Renderer) {
+	renderersMu.Lock()
+	defer renderersMu.Unlock()
+	renderers[name] = r
+}
+
+// Render looks up the renderer selected by cmd's --output flag and uses it to write v to
+// cmd.OutOrStdout()
+func Render(cmd *cobra.Command, v interface{}) error {
+	format, err := cmd.Flags().GetString(FlagName)
+	if err != nil {
+		format = DefaultFormat
+	}
+
+	renderersMu.Lock()
+	r, ok := renderers[format]
+	renderersMu.Unlock()
+	if !ok {
+		return fmt.Errorf("unknown output format %q", format)
+	}
+
+	return r.Render(cmd.OutOrStdout(), v)
+}
+
+// renderText writes v using its Stringer implementation when it has one, falling back to a
+// Go-syntax representation otherwise
+func renderText(w io.Writer, v interface{}) error {
+	if s, ok := v.(fmt.Stringer); ok {
+		_, err := fmt.Fprintln(w, s.String())
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%+v\n", v)
+	return err
+}
+
+func renderJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func renderYAML(w io.Writer, v interface{}) error {
+	enc := yaml.NewEncoder(w)
+	defer enc.Close()
+	return enc.Encode(v)
+}